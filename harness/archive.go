@@ -0,0 +1,262 @@
+package harness
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ArchiveFormat selects the container ArchiveWriter bundles files into.
+type ArchiveFormat int
+
+const (
+	// ArchiveFormatTarGz bundles files into a .tar.gz, the default.
+	ArchiveFormatTarGz ArchiveFormat = iota
+	// ArchiveFormatZip bundles files into a .zip.
+	ArchiveFormatZip
+)
+
+// archiveManifestEntry records the scope a bundled file was downloaded from, so
+// RestoreFromArchive knows which account/org/project to re-upload it to.
+type archiveManifestEntry struct {
+	Path    string `json:"path"`
+	Account string `json:"account"`
+	Org     string `json:"org,omitempty"`
+	Project string `json:"project,omitempty"`
+	Size    int64  `json:"size"`
+}
+
+// ArchiveWriter is an Output that streams downloaded files into a single .tar.gz or
+// .zip bundle instead of laying them out on disk, preserving the same
+// filestore/<scope>/<path> layout a DiskOutput would use. A top-level manifest.json
+// describing each entry's account/org/project scope is written when Close is called.
+type ArchiveWriter struct {
+	mu       sync.Mutex
+	format   ArchiveFormat
+	f        *os.File
+	gw       *gzip.Writer
+	tw       *tar.Writer
+	zw       *zip.Writer
+	manifest []archiveManifestEntry
+}
+
+// NewArchiveWriter creates the bundle at path, truncating any existing file.
+func NewArchiveWriter(path string, format ArchiveFormat) (*ArchiveWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating archive %s: %w", path, err)
+	}
+
+	aw := &ArchiveWriter{format: format, f: f}
+	if format == ArchiveFormatZip {
+		aw.zw = zip.NewWriter(f)
+	} else {
+		aw.gw = gzip.NewWriter(f)
+		aw.tw = tar.NewWriter(aw.gw)
+	}
+	return aw, nil
+}
+
+// Write implements Output.
+func (a *ArchiveWriter) Write(account, org, project, folder, path string, data []byte) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	name := filepath.ToSlash(filepath.Join("filestore", folder, path))
+	if err := a.writeEntry(name, data); err != nil {
+		return 0, err
+	}
+
+	a.manifest = append(a.manifest, archiveManifestEntry{
+		Path:    name,
+		Account: account,
+		Org:     org,
+		Project: project,
+		Size:    int64(len(data)),
+	})
+	return int64(len(data)), nil
+}
+
+func (a *ArchiveWriter) writeEntry(name string, data []byte) error {
+	if a.format == ArchiveFormatZip {
+		w, err := a.zw.Create(name)
+		if err != nil {
+			return fmt.Errorf("adding %s to archive: %w", name, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("writing %s to archive: %w", name, err)
+		}
+		return nil
+	}
+
+	if err := a.tw.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0644}); err != nil {
+		return fmt.Errorf("adding %s to archive: %w", name, err)
+	}
+	if _, err := a.tw.Write(data); err != nil {
+		return fmt.Errorf("writing %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// Close writes the top-level manifest.json describing every entry's scope, then
+// finalizes and closes the underlying archive file.
+func (a *ArchiveWriter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	manifestJSON, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding archive manifest: %w", err)
+	}
+	if err := a.writeEntry("manifest.json", manifestJSON); err != nil {
+		return err
+	}
+
+	if a.format == ArchiveFormatZip {
+		if err := a.zw.Close(); err != nil {
+			return fmt.Errorf("closing archive: %w", err)
+		}
+	} else {
+		if err := a.tw.Close(); err != nil {
+			return fmt.Errorf("closing archive: %w", err)
+		}
+		if err := a.gw.Close(); err != nil {
+			return fmt.Errorf("closing archive: %w", err)
+		}
+	}
+	return a.f.Close()
+}
+
+// RestoreFromArchive reads a bundle written by ArchiveWriter and re-uploads each of
+// its entries to a target Harness instance via the file-store create API, using the
+// account/org/project scope recorded in the bundle's manifest.json.
+func RestoreFromArchive(api *APIRequest, archivePath string) (Summary, error) {
+	entries, scopes, err := readArchive(archivePath)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{Errors: map[string]error{}}
+	for _, scope := range scopes {
+		data, ok := entries[scope.Path]
+		if !ok {
+			summary.Failed++
+			summary.Errors[scope.Path] = fmt.Errorf("archive missing content for %s", scope.Path)
+			continue
+		}
+		if err := uploadFile(api, scope, data); err != nil {
+			summary.Failed++
+			summary.Errors[scope.Path] = err
+			continue
+		}
+		summary.Succeeded++
+	}
+	return summary, nil
+}
+
+// readArchive extracts every entry from a bundle written by ArchiveWriter, returning
+// entry content by path and the manifest describing each entry's scope.
+func readArchive(archivePath string) (map[string][]byte, []archiveManifestEntry, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening archive %s: %w", archivePath, err)
+	}
+	defer f.Close()
+
+	entries := map[string][]byte{}
+
+	if strings.HasSuffix(archivePath, ".zip") {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading archive %s: %w", archivePath, err)
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading archive %s: %w", archivePath, err)
+		}
+		for _, zf := range zr.File {
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading %s from archive: %w", zf.Name, err)
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading %s from archive: %w", zf.Name, err)
+			}
+			entries[zf.Name] = data
+		}
+	} else {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading archive %s: %w", archivePath, err)
+		}
+		defer gr.Close()
+
+		tr := tar.NewReader(gr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading archive %s: %w", archivePath, err)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, nil, fmt.Errorf("reading %s from archive: %w", hdr.Name, err)
+			}
+			entries[hdr.Name] = data
+		}
+	}
+
+	manifestJSON, ok := entries["manifest.json"]
+	if !ok {
+		return nil, nil, fmt.Errorf("archive %s has no manifest.json", archivePath)
+	}
+	var scopes []archiveManifestEntry
+	if err := json.Unmarshal(manifestJSON, &scopes); err != nil {
+		return nil, nil, fmt.Errorf("parsing manifest.json in %s: %w", archivePath, err)
+	}
+
+	return entries, scopes, nil
+}
+
+// uploadFile re-uploads a single archive entry via the file-store create API.
+func uploadFile(api *APIRequest, entry archiveManifestEntry, data []byte) error {
+	name := filepath.Base(entry.Path)
+	identifier := strings.TrimSuffix(name, filepath.Ext(name))
+
+	resp, err := api.Client.R().
+		SetHeader("x-api-key", api.APIKey).
+		SetQueryParams(map[string]string{
+			"accountIdentifier": entry.Account,
+			"orgIdentifier":     entry.Org,
+			"projectIdentifier": entry.Project,
+		}).
+		SetFileReader("content", name, bytes.NewReader(data)).
+		SetFormData(map[string]string{
+			"identifier": identifier,
+			"name":       name,
+			"type":       "FILE",
+		}).
+		Post(fmt.Sprintf("%s/gateway/file-store/files", api.BaseURL))
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", entry.Path, err)
+	}
+	if resp.StatusCode() >= 300 {
+		return &apiError{
+			statusCode: resp.StatusCode(),
+			err:        fmt.Errorf("API error uploading %s: %d %s", entry.Path, resp.StatusCode(), resp.String()),
+		}
+	}
+	return nil
+}
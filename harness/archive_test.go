@@ -0,0 +1,111 @@
+package harness
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveWriterTarGzRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "bundle.tar.gz")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("archived content"))
+	}))
+	defer server.Close()
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	files := []FileStoreContent{{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"}}
+
+	aw, err := NewArchiveWriter(archivePath, ArchiveFormatTarGz)
+	require.NoError(t, err)
+
+	summary, err := DownloadFiles(api, files, "test-account", "test-org", "", "/test-org", DownloadOptions{
+		Workers: 1,
+		Output:  aw,
+	})
+	require.NoError(t, err)
+	require.NoError(t, aw.Close())
+	assert.Equal(t, 1, summary.Succeeded)
+
+	_, statErr := os.Stat("./filestore")
+	assert.True(t, os.IsNotExist(statErr), "archive output should not touch ./filestore on disk")
+
+	entries, scopes, err := readArchive(archivePath)
+	require.NoError(t, err)
+	require.Len(t, scopes, 1)
+	assert.Equal(t, "filestore/test-org/a.yaml", scopes[0].Path)
+	assert.Equal(t, "test-account", scopes[0].Account)
+	assert.Equal(t, "test-org", scopes[0].Org)
+	assert.Equal(t, "archived content", string(entries["filestore/test-org/a.yaml"]))
+}
+
+func TestArchiveWriterZipRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "bundle.zip")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("zipped content"))
+	}))
+	defer server.Close()
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	file := FileStoreContent{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"}
+
+	aw, err := NewArchiveWriter(archivePath, ArchiveFormatZip)
+	require.NoError(t, err)
+
+	require.NoError(t, file.DownloadFile(api, "test-account", "", "", "/account", DownloadOptions{Output: aw}))
+	require.NoError(t, aw.Close())
+
+	entries, scopes, err := readArchive(archivePath)
+	require.NoError(t, err)
+	require.Len(t, scopes, 1)
+	assert.Equal(t, "filestore/account/a.yaml", scopes[0].Path)
+	assert.Equal(t, "zipped content", string(entries["filestore/account/a.yaml"]))
+}
+
+func TestRestoreFromArchiveUploadsEntries(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "bundle.tar.gz")
+
+	var uploadedAccount, uploadedName string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploadedAccount = r.URL.Query().Get("accountIdentifier")
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		uploadedName = r.FormValue("name")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	downloadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("restorable content"))
+	}))
+	defer downloadServer.Close()
+
+	downloadAPI := &APIRequest{BaseURL: downloadServer.URL, Client: resty.New(), APIKey: "test-api-key"}
+	file := FileStoreContent{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"}
+
+	aw, err := NewArchiveWriter(archivePath, ArchiveFormatTarGz)
+	require.NoError(t, err)
+	require.NoError(t, file.DownloadFile(downloadAPI, "source-account", "", "", "/account", DownloadOptions{Output: aw}))
+	require.NoError(t, aw.Close())
+
+	uploadAPI := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	summary, err := RestoreFromArchive(uploadAPI, archivePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, summary.Succeeded)
+	assert.Equal(t, "source-account", uploadedAccount)
+	assert.Equal(t, "a.yaml", uploadedName)
+}
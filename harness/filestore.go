@@ -0,0 +1,384 @@
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// APIRequest holds the shared HTTP client configuration used to talk to a Harness account.
+type APIRequest struct {
+	BaseURL string
+	Client  *resty.Client
+	APIKey  string
+}
+
+// FileStoreContent describes a single entry returned by the Harness file store list API.
+type FileStoreContent struct {
+	Identifier string
+	Name       string
+	Path       string
+	// Checksum is the digest the list-files API reported for this entry, if any. When
+	// set, DownloadFile verifies the downloaded bytes against it before the file is
+	// written to its final location.
+	Checksum string
+	// ChecksumAlgo names the algorithm Checksum was computed with (e.g. "sha256",
+	// "sha1", "md5"). Defaults to "sha256" when empty.
+	ChecksumAlgo string
+}
+
+// DownloadOptions configures how files are pulled from the file store. The zero value
+// applies the defaults documented on each field. DownloadFile makes a single attempt
+// per call and does not use Workers, MaxRetries, RetryBackoff or Progress.
+type DownloadOptions struct {
+	// Context governs cancellation of the download. Defaults to context.Background().
+	Context context.Context
+	// Workers is the number of concurrent download workers used by DownloadFiles.
+	// Defaults to runtime.NumCPU() when <= 0.
+	Workers int
+	// MaxRetries is the number of additional attempts made after a transient 5xx/429
+	// response. Defaults to 3 when <= 0.
+	MaxRetries int
+	// RetryBackoff is the base delay used for exponential backoff between retries.
+	// Defaults to 500ms when <= 0.
+	RetryBackoff time.Duration
+	// Progress, when set, receives one event per completed file. Sends are best-effort
+	// and abandoned if the context is cancelled, so callers should keep it buffered or
+	// drain it on a separate goroutine.
+	Progress chan<- ProgressEvent
+
+	// IgnoreFile, when set, is parsed as a .harnessignore-style file and combined with
+	// Include/Exclude into an IgnoreMatcher evaluated against each file's logical path
+	// (folder + file.Path) before it is downloaded.
+	IgnoreFile string
+	// Include re-includes paths otherwise excluded, applied after Exclude and IgnoreFile.
+	Include []string
+	// Exclude skips paths matching any of these gitignore-style globs.
+	Exclude []string
+
+	// Output is where verified bytes are written. Defaults to DiskOutput, the
+	// historical ./filestore/<folder> layout; pass an *ArchiveWriter to bundle
+	// downloads into a .tar.gz or .zip instead.
+	Output Output
+}
+
+// output resolves the configured Output, defaulting to DiskOutput.
+func (o DownloadOptions) output() Output {
+	if o.Output == nil {
+		return DiskOutput{}
+	}
+	return o.Output
+}
+
+// matcher builds the IgnoreMatcher described by these options. A zero value options
+// produces a matcher that filters nothing.
+func (o DownloadOptions) matcher() (*IgnoreMatcher, error) {
+	if o.IgnoreFile == "" && len(o.Include) == 0 && len(o.Exclude) == 0 {
+		return nil, nil
+	}
+	return NewIgnoreMatcher(o.IgnoreFile, o.Include, o.Exclude)
+}
+
+// ProgressEvent reports the outcome of a single file within a batch download.
+type ProgressEvent struct {
+	File         FileStoreContent
+	Current      int
+	Total        int
+	BytesWritten int64
+	Elapsed      time.Duration
+	// Skipped is true when the file was filtered out or already up to date on disk.
+	Skipped bool
+	Err     error
+}
+
+// Summary aggregates the outcome of a batch download.
+type Summary struct {
+	Succeeded int
+	Skipped   int
+	Failed    int
+	// Errors maps a failed file's path (file.Path for DownloadFiles, the archive entry
+	// path for RestoreFromArchive) to the error that failed it.
+	Errors map[string]error
+}
+
+// DownloadFile fetches a single file store entry and writes it to opts' Output (by
+// default beneath ./filestore/<folder>), mirroring the logical path reported by the
+// list-files API. opts is optional; when
+// given, its IgnoreFile/Include/Exclude patterns are evaluated against the file's
+// logical path (folder + f.Path) and a match is skipped without error. If f.Checksum
+// already matches what's on disk per ./filestore/.manifest.json, the download is
+// skipped; otherwise the bytes are verified against f.Checksum before being written.
+func (f FileStoreContent) DownloadFile(api *APIRequest, account, org, project, folder string, opts ...DownloadOptions) error {
+	var o DownloadOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	matcher, err := o.matcher()
+	if err != nil {
+		return err
+	}
+	if matcher.Match(folder + f.Path) {
+		return nil
+	}
+
+	out := o.output()
+	var man *manifest
+	if _, isDisk := out.(DiskOutput); isDisk {
+		if man, err = loadManifest(manifestPath); err != nil {
+			return err
+		}
+	}
+
+	ctx := o.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, _, err := downloadFile(ctx, api, f, account, org, project, folder, man, out); err != nil {
+		return err
+	}
+	if man != nil {
+		return man.save()
+	}
+	return nil
+}
+
+// DownloadFiles downloads files in parallel across a bounded worker pool, retrying
+// transient 5xx/429 responses with exponential backoff. It returns as soon as every
+// file has been attempted (or ctx is cancelled), aggregating successes and failures
+// into a Summary rather than failing the whole batch on the first error.
+func DownloadFiles(api *APIRequest, files []FileStoreContent, account, org, project, folder string, opts DownloadOptions) (Summary, error) {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	maxRetries := opts.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	backoff := opts.RetryBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	matcher, err := opts.matcher()
+	if err != nil {
+		return Summary{}, err
+	}
+	out := opts.output()
+	var man *manifest
+	if _, isDisk := out.(DiskOutput); isDisk {
+		if man, err = loadManifest(manifestPath); err != nil {
+			return Summary{}, err
+		}
+	}
+
+	total := len(files)
+	summary := Summary{Errors: map[string]error{}}
+
+	emit := func(ev ProgressEvent) {
+		if opts.Progress == nil {
+			return
+		}
+		select {
+		case opts.Progress <- ev:
+		case <-ctx.Done():
+		}
+	}
+
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	worker := func() {
+		defer wg.Done()
+		for idx := range jobs {
+			file := files[idx]
+			start := time.Now()
+
+			if matcher.Match(folder + file.Path) {
+				mu.Lock()
+				summary.Skipped++
+				mu.Unlock()
+				emit(ProgressEvent{File: file, Current: idx + 1, Total: total, Elapsed: time.Since(start), Skipped: true})
+				continue
+			}
+
+			written, skipped, err := downloadWithRetry(ctx, api, file, account, org, project, folder, maxRetries, backoff, man, out)
+
+			mu.Lock()
+			switch {
+			case err != nil:
+				summary.Failed++
+				summary.Errors[file.Path] = err
+			case skipped:
+				summary.Skipped++
+			default:
+				summary.Succeeded++
+			}
+			mu.Unlock()
+
+			emit(ProgressEvent{
+				File:         file,
+				Current:      idx + 1,
+				Total:        total,
+				BytesWritten: written,
+				Elapsed:      time.Since(start),
+				Skipped:      skipped,
+				Err:          err,
+			})
+		}
+	}
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+
+feed:
+	for i := range files {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if man != nil {
+		if err := man.save(); err != nil {
+			return summary, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// downloadWithRetry wraps downloadFile, retrying transient 5xx/429 responses with
+// exponential backoff. The last error encountered is returned if all attempts fail.
+func downloadWithRetry(ctx context.Context, api *APIRequest, file FileStoreContent, account, org, project, folder string, maxRetries int, backoff time.Duration, man *manifest, out Output) (int64, bool, error) {
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			wait := time.Duration(float64(backoff) * math.Pow(2, float64(attempt-1)))
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return 0, false, ctx.Err()
+			}
+		}
+
+		written, skipped, err := downloadFile(ctx, api, file, account, org, project, folder, man, out)
+		if err == nil {
+			return written, skipped, nil
+		}
+		lastErr = err
+		if !isRetryable(err) {
+			return 0, false, err
+		}
+	}
+	return 0, false, lastErr
+}
+
+// apiError carries the HTTP status code of a failed file store request so callers
+// can distinguish transient failures (5xx, 429) from permanent ones (4xx).
+type apiError struct {
+	statusCode int
+	err        error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+func (e *apiError) Unwrap() error { return e.err }
+
+func isRetryable(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.statusCode == http.StatusTooManyRequests || apiErr.statusCode >= 500
+	}
+	return false
+}
+
+// downloadFile performs the actual HTTP download for a single file, returning the
+// number of bytes written and whether the download was skipped because an unchanged
+// copy already exists on disk (per man; only ever true for the default DiskOutput).
+// The downloaded bytes are verified against file.Checksum, if set, before out.Write
+// is called, so a mismatch is reported as an error without ever being written.
+func downloadFile(ctx context.Context, api *APIRequest, file FileStoreContent, account, org, project, folder string, man *manifest, out Output) (int64, bool, error) {
+	logicalPath := folder + file.Path
+
+	if _, isDisk := out.(DiskOutput); isDisk && man != nil {
+		destPath := filepath.Join("filestore", folder, file.Path)
+		if man.shouldSkip(logicalPath, file, destPath) {
+			return 0, true, nil
+		}
+	}
+
+	resp, err := api.Client.R().
+		SetContext(ctx).
+		SetHeader("x-api-key", api.APIKey).
+		SetQueryParams(map[string]string{
+			"accountIdentifier": account,
+			"orgIdentifier":     org,
+			"projectIdentifier": project,
+		}).
+		Get(fmt.Sprintf("%s/gateway/file-store/files/%s/download", api.BaseURL, file.Identifier))
+	if err != nil {
+		return 0, false, fmt.Errorf("downloading file %s: %w", file.Path, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return 0, false, &apiError{
+			statusCode: resp.StatusCode(),
+			err:        fmt.Errorf("API error downloading file %s: %d %s", file.Path, resp.StatusCode(), resp.String()),
+		}
+	}
+
+	body := resp.Body()
+
+	algo := file.ChecksumAlgo
+	if algo == "" {
+		algo = "sha256"
+	}
+	sum, err := digest(algo, body)
+	if err != nil {
+		return 0, false, fmt.Errorf("verifying %s: %w", file.Path, err)
+	}
+	if file.Checksum != "" && sum != file.Checksum {
+		return 0, false, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", file.Path, file.Checksum, sum)
+	}
+
+	written, err := out.Write(account, org, project, folder, file.Path, body)
+	if err != nil {
+		return written, false, err
+	}
+
+	if _, isDisk := out.(DiskOutput); isDisk && man != nil {
+		destPath := filepath.Join("filestore", folder, file.Path)
+		if info, statErr := os.Stat(destPath); statErr == nil {
+			man.set(logicalPath, manifestEntry{
+				Digest:  sum,
+				Algo:    algo,
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+				Source:  file.Identifier,
+			})
+		}
+	}
+
+	return written, false, nil
+}
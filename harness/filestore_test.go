@@ -1,140 +1,22 @@
 package harness
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestFileStoreDownload(t *testing.T) {
-	// Create a test server to mock the Harness API
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Simulate file download response
-		if strings.Contains(r.URL.Path, "/download") {
-			w.Header().Set("Content-Type", "application/octet-stream")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("test file content"))
-			return
-		}
-		w.WriteHeader(http.StatusNotFound)
-	}))
-	defer server.Close()
-
-	// Setup test directory
-	testDir := "./test_filestore"
-	defer os.RemoveAll(testDir)
-
-	// Create API client
-	api := &APIRequest{
-		BaseURL: server.URL,
-		Client:  resty.New(),
-		APIKey:  "test-api-key",
-	}
-
-	tests := []struct {
-		name           string
-		file           FileStoreContent
-		account        string
-		org            string
-		project        string
-		folder         string
-		expectedPath   string
-		expectError    bool
-	}{
-		{
-			name: "Account level file download",
-			file: FileStoreContent{
-				Identifier: "test-file-1",
-				Name:       "test-file.yaml",
-				Path:       "/manifests/test-file.yaml",
-			},
-			account:      "test-account",
-			org:          "",
-			project:      "",
-			folder:       "/account",
-			expectedPath: "./filestore/account/manifests/test-file.yaml",
-			expectError:  false,
-		},
-		{
-			name: "Org level file download",
-			file: FileStoreContent{
-				Identifier: "test-file-2",
-				Name:       "org-file.yaml",
-				Path:       "/configs/org-file.yaml",
-			},
-			account:      "test-account",
-			org:          "test-org",
-			project:      "",
-			folder:       "/test-org",
-			expectedPath: "./filestore/test-org/configs/org-file.yaml",
-			expectError:  false,
-		},
-		{
-			name: "Project level file download",
-			file: FileStoreContent{
-				Identifier: "test-file-3",
-				Name:       "project-file.yaml",
-				Path:       "/templates/project-file.yaml",
-			},
-			account:      "test-account",
-			org:          "test-org",
-			project:      "test-project",
-			folder:       "/test-org/test-project",
-			expectedPath: "./filestore/test-org/test-project/templates/project-file.yaml",
-			expectError:  false,
-		},
-		{
-			name: "File without extension (should be skipped)",
-			file: FileStoreContent{
-				Identifier: "test-folder",
-				Name:       "folder",
-				Path:       "/folder",
-			},
-			account:      "test-account",
-			org:          "",
-			project:      "",
-			folder:       "/account",
-			expectedPath: "", // No file should be created
-			expectError:  false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Clean up before each test
-			os.RemoveAll("./filestore")
-
-			err := tt.file.DownloadFile(api, tt.account, tt.org, tt.project, tt.folder)
-
-			if tt.expectError {
-				assert.Error(t, err)
-				return
-			}
-
-			assert.NoError(t, err)
-
-			if tt.expectedPath != "" {
-				// Check if file was created at expected path
-				assert.FileExists(t, tt.expectedPath, "File should exist at expected path")
-
-				// Check file content
-				content, err := ioutil.ReadFile(tt.expectedPath)
-				require.NoError(t, err)
-				assert.Equal(t, "test file content", string(content))
-			}
-		})
-	}
-}
-
 func TestFileStorePathCorrection(t *testing.T) {
 	// Test that file paths are correctly constructed without duplicate "filestore" directories
 	tests := []struct {
@@ -202,31 +84,6 @@ func TestDirectoryCreation(t *testing.T) {
 	assert.Equal(t, content, string(readContent))
 }
 
-func TestFileStoreAPIError(t *testing.T) {
-	// Create a test server that returns errors
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusUnauthorized)
-		w.Write([]byte(`{"status":"ERROR","message":"Unauthorized","correlationId":"test-123"}`))
-	}))
-	defer server.Close()
-
-	api := &APIRequest{
-		BaseURL: server.URL,
-		Client:  resty.New(),
-		APIKey:  "invalid-key",
-	}
-
-	file := FileStoreContent{
-		Identifier: "test-file",
-		Name:       "test-file.yaml",
-		Path:       "/test-file.yaml",
-	}
-
-	err := file.DownloadFile(api, "test-account", "", "", "/account")
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "API error downloading file")
-}
-
 // TestFileStoreIntegration tests the complete filestore workflow
 func TestFileStoreIntegration(t *testing.T) {
 	// This test simulates the complete filestore download and git setup process
@@ -278,6 +135,128 @@ func TestFileStoreIntegration(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDownloadFilesParallel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("content for " + r.URL.Path))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+
+	files := []FileStoreContent{
+		{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"},
+		{Identifier: "f2", Name: "b.yaml", Path: "/b.yaml"},
+		{Identifier: "f3", Name: "c.yaml", Path: "/c.yaml"},
+		{Identifier: "f4", Name: "secret.yaml", Path: "/secrets/secret.yaml"},
+	}
+
+	events := make(chan ProgressEvent, len(files))
+	summary, err := DownloadFiles(api, files, "test-account", "", "", "/account", DownloadOptions{
+		Workers:  2,
+		Progress: events,
+		Exclude:  []string{"**/secrets/**"},
+	})
+	close(events)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Succeeded)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Equal(t, 0, summary.Failed)
+	assert.Empty(t, summary.Errors)
+
+	seen := 0
+	for range events {
+		seen++
+	}
+	assert.Equal(t, len(files), seen)
+
+	assert.FileExists(t, "./filestore/account/a.yaml")
+	assert.FileExists(t, "./filestore/account/b.yaml")
+	assert.FileExists(t, "./filestore/account/c.yaml")
+	assert.NoFileExists(t, "./filestore/account/secrets/secret.yaml")
+}
+
+func TestDownloadFilesRetriesTransientErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("eventually ok"))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	files := []FileStoreContent{{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"}}
+
+	summary, err := DownloadFiles(api, files, "test-account", "", "", "/account", DownloadOptions{
+		Workers:      1,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Succeeded)
+	assert.True(t, attempts >= 3)
+}
+
+func TestDownloadFilesFailsPermanentErrorsWithoutRetry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"status":"ERROR","message":"Unauthorized"}`))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	files := []FileStoreContent{{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"}}
+
+	summary, err := DownloadFiles(api, files, "test-account", "", "", "/account", DownloadOptions{
+		Workers:      1,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, int32(1), attempts)
+	assert.Contains(t, summary.Errors["/a.yaml"].Error(), "API error downloading file")
+}
+
+func TestDownloadFilesRespectsContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	files := []FileStoreContent{
+		{Identifier: "f1", Name: "a.yaml", Path: "/a.yaml"},
+		{Identifier: "f2", Name: "b.yaml", Path: "/b.yaml"},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err := DownloadFiles(api, files, "test-account", "", "", "/account", DownloadOptions{
+		Context: ctx,
+		Workers: 1,
+	})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func BenchmarkFileStoreDownload(b *testing.B) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/octet-stream")
@@ -304,6 +283,3 @@ func BenchmarkFileStoreDownload(b *testing.B) {
 		file.DownloadFile(api, "test-account", "", "", "/account")
 	}
 }
-
-
-
@@ -0,0 +1,137 @@
+package harness_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/frankstutz-at-globality/HarnessRemoteMigrator/harness"
+	"github.com/frankstutz-at-globality/HarnessRemoteMigrator/harness/testutil"
+)
+
+// These tests replay cassettes recorded once against a sandbox Harness account
+// (see testutil.NewRecordingAPI), so they exercise the real response shape of the
+// file-store download endpoint without needing network access or live credentials.
+
+func TestFileStoreDownload(t *testing.T) {
+	api := testutil.NewReplayingAPI(t, "download-success")
+
+	tests := []struct {
+		name         string
+		file         harness.FileStoreContent
+		account      string
+		org          string
+		project      string
+		folder       string
+		opts         harness.DownloadOptions
+		expectedPath string
+		expectError  bool
+	}{
+		{
+			name: "Account level file download",
+			file: harness.FileStoreContent{
+				Identifier: "test-file-1",
+				Name:       "test-file.yaml",
+				Path:       "/manifests/test-file.yaml",
+			},
+			account:      "test-account",
+			org:          "",
+			project:      "",
+			folder:       "/account",
+			expectedPath: "./filestore/account/manifests/test-file.yaml",
+			expectError:  false,
+		},
+		{
+			name: "Org level file download",
+			file: harness.FileStoreContent{
+				Identifier: "test-file-2",
+				Name:       "org-file.yaml",
+				Path:       "/configs/org-file.yaml",
+			},
+			account:      "test-account",
+			org:          "test-org",
+			project:      "",
+			folder:       "/test-org",
+			expectedPath: "./filestore/test-org/configs/org-file.yaml",
+			expectError:  false,
+		},
+		{
+			name: "Project level file download",
+			file: harness.FileStoreContent{
+				Identifier: "test-file-3",
+				Name:       "project-file.yaml",
+				Path:       "/templates/project-file.yaml",
+			},
+			account:      "test-account",
+			org:          "test-org",
+			project:      "test-project",
+			folder:       "/test-org/test-project",
+			expectedPath: "./filestore/test-org/test-project/templates/project-file.yaml",
+			expectError:  false,
+		},
+		{
+			name: "File without extension is downloaded like any other entry",
+			file: harness.FileStoreContent{
+				Identifier: "test-noext",
+				Name:       "config",
+				Path:       "/config",
+			},
+			account:      "test-account",
+			org:          "",
+			project:      "",
+			folder:       "/account",
+			expectedPath: "./filestore/account/config",
+			expectError:  false,
+		},
+		{
+			name: "File matching an exclude pattern is skipped",
+			file: harness.FileStoreContent{
+				Identifier: "test-folder",
+				Name:       "folder",
+				Path:       "/folder",
+			},
+			account:      "test-account",
+			org:          "",
+			project:      "",
+			folder:       "/account",
+			opts:         harness.DownloadOptions{Exclude: []string{"folder"}},
+			expectedPath: "", // No file should be created; DownloadFile never reaches the cassette
+			expectError:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer os.RemoveAll("./filestore")
+
+			err := tt.file.DownloadFile(api, tt.account, tt.org, tt.project, tt.folder, tt.opts)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.expectedPath != "" {
+				assert.FileExists(t, tt.expectedPath, "File should exist at expected path")
+			}
+		})
+	}
+}
+
+func TestFileStoreAPIError(t *testing.T) {
+	defer os.RemoveAll("./filestore")
+
+	api := testutil.NewReplayingAPI(t, "download-error")
+	file := harness.FileStoreContent{
+		Identifier: "test-file",
+		Name:       "test-file.yaml",
+		Path:       "/test-file.yaml",
+	}
+
+	err := file.DownloadFile(api, "test-account", "", "", "/account")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "API error downloading file")
+}
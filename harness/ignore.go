@@ -0,0 +1,166 @@
+package harness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreMatcher filters file store paths using gitignore-compatible glob rules: a
+// leading "!" re-includes a path excluded by an earlier rule, "**" matches any number
+// of path segments, and a trailing "/" restricts the rule to directories. Rules are
+// evaluated in order, so later rules take precedence over earlier ones.
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// NewIgnoreMatcher builds a matcher from an optional ignore file (one pattern per
+// line, "#" comments and blank lines ignored) plus explicit include/exclude globs.
+// Include patterns are applied as re-include ("!") rules after exclude patterns and
+// the ignore file, so they can carve out exceptions to broader excludes.
+func NewIgnoreMatcher(ignoreFile string, include, exclude []string) (*IgnoreMatcher, error) {
+	m := &IgnoreMatcher{}
+
+	if ignoreFile != "" {
+		data, err := os.ReadFile(ignoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ignore file %s: %w", ignoreFile, err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if rule, ok := parseIgnoreRule(line); ok {
+				m.rules = append(m.rules, rule)
+			}
+		}
+	}
+
+	for _, pattern := range exclude {
+		if rule, ok := parseIgnoreRule(pattern); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	for _, pattern := range include {
+		if rule, ok := parseIgnoreRule("!" + pattern); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+
+	return m, nil
+}
+
+// Match reports whether path should be skipped. A nil matcher matches nothing, so
+// callers can use it unconditionally without a nil check.
+func (m *IgnoreMatcher) Match(path string) bool {
+	if m == nil {
+		return false
+	}
+
+	path = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.matches(path) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(trimmed, "!") {
+		rule.negate = true
+		trimmed = trimmed[1:]
+	}
+	if strings.HasSuffix(trimmed, "/") {
+		rule.dirOnly = true
+		trimmed = strings.TrimSuffix(trimmed, "/")
+	}
+	if strings.Contains(trimmed, "/") {
+		rule.anchored = true
+	}
+	rule.pattern = strings.TrimPrefix(trimmed, "/")
+	if rule.pattern == "" {
+		return ignoreRule{}, false
+	}
+	return rule, true
+}
+
+// matches reports whether the rule applies to path. Other rules match either the leaf
+// path or one of its ancestor directories, so a pattern naming a directory also
+// ignores every file beneath it. dirOnly rules only match a file
+// nested beneath a matching directory (via matchAncestor), never the leaf path
+// itself, since a trailing "/" restricts the rule to directories and the matcher
+// only ever sees leaf file paths rather than real directory entries.
+func (r ignoreRule) matches(path string) bool {
+	segs := strings.Split(path, "/")
+	patternSegs := strings.Split(r.pattern, "/")
+
+	if r.dirOnly {
+		if r.anchored {
+			return matchAncestor(patternSegs, segs)
+		}
+		for i := range segs {
+			if matchAncestor(patternSegs, segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if r.anchored {
+		return matchSegments(patternSegs, segs) || matchAncestor(patternSegs, segs)
+	}
+	for i := range segs {
+		if matchSegments(patternSegs, segs[i:]) || matchAncestor(patternSegs, segs[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAncestor reports whether pattern matches one of path's ancestor directories,
+// i.e. whether a rule applies to a descendant file through its parent directory.
+func matchAncestor(patternSegs, pathSegs []string) bool {
+	for i := 1; i < len(pathSegs); i++ {
+		if matchSegments(patternSegs, pathSegs[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches a gitignore-style glob pattern (split on "/", "**" matching
+// zero or more segments) against a path split the same way.
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		if matchSegments(pattern[1:], path) {
+			return true
+		}
+		if len(path) == 0 {
+			return false
+		}
+		return matchSegments(pattern, path[1:])
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,52 @@
+package harness
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreMatcherExcludeAndReinclude(t *testing.T) {
+	m, err := NewIgnoreMatcher("", []string{"keep.yaml"}, []string{"*.yaml"})
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("account/manifests/drop.yaml"), "excluded by *.yaml")
+	assert.False(t, m.Match("account/manifests/keep.yaml"), "re-included by Include")
+	assert.False(t, m.Match("account/manifests/drop.json"), "not matched by *.yaml")
+}
+
+func TestIgnoreMatcherDoubleStar(t *testing.T) {
+	m, err := NewIgnoreMatcher("", nil, []string{"**/node_modules/**"})
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("account/project/node_modules/left-pad/index.js"))
+	assert.False(t, m.Match("account/project/src/index.js"))
+}
+
+func TestIgnoreMatcherFromFile(t *testing.T) {
+	dir := t.TempDir()
+	ignoreFile := filepath.Join(dir, ".harnessignore")
+	require.NoError(t, os.WriteFile(ignoreFile, []byte("# comment\n*.secret.yaml\n!public.secret.yaml\n"), 0644))
+
+	m, err := NewIgnoreMatcher(ignoreFile, nil, nil)
+	require.NoError(t, err)
+
+	assert.True(t, m.Match("account/db.secret.yaml"))
+	assert.False(t, m.Match("account/public.secret.yaml"))
+}
+
+func TestIgnoreMatcherDirOnlyDoesNotMatchFile(t *testing.T) {
+	m, err := NewIgnoreMatcher("", nil, []string{"dist/"})
+	require.NoError(t, err)
+
+	assert.False(t, m.Match("account/dist"), "dist is a file here, not a directory")
+	assert.True(t, m.Match("account/dist/bundle.js"), "files under a dist/ directory are still ignored")
+}
+
+func TestIgnoreMatcherNilIsPermissive(t *testing.T) {
+	var m *IgnoreMatcher
+	assert.False(t, m.Match("anything/at/all.yaml"))
+}
@@ -0,0 +1,122 @@
+package harness
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// manifestPath records the digest of every file a migration has written, keyed by
+// its logical path (folder + file.Path), so that re-running a migration can skip
+// files that are already present and unchanged on disk.
+const manifestPath = "filestore/.manifest.json"
+
+// manifestEntry is the recorded state of one previously downloaded file.
+type manifestEntry struct {
+	Digest  string    `json:"digest"`
+	Algo    string    `json:"algo"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Source  string    `json:"source"`
+}
+
+// manifest is the in-memory, file-backed table of manifestEntry values at manifestPath.
+type manifest struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]manifestEntry
+}
+
+// loadManifest reads the manifest at path, returning an empty manifest if it doesn't exist yet.
+func loadManifest(path string) (*manifest, error) {
+	m := &manifest{path: path, entries: map[string]manifestEntry{}}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &m.entries); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+func (m *manifest) get(logicalPath string) (manifestEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[logicalPath]
+	return e, ok
+}
+
+func (m *manifest) set(logicalPath string, entry manifestEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[logicalPath] = entry
+}
+
+// save writes the manifest back to disk as indented JSON.
+func (m *manifest) save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := json.MarshalIndent(m.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest %s: %w", m.path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("creating directory for manifest %s: %w", m.path, err)
+	}
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return fmt.Errorf("writing manifest %s: %w", m.path, err)
+	}
+	return nil
+}
+
+// shouldSkip reports whether destPath already holds the expected content: the manifest
+// must have recorded a digest matching file.Checksum, and destPath's current size and
+// modification time must still match what was recorded, so an external edit or delete
+// is always caught without needing to re-hash every file on every run.
+func (m *manifest) shouldSkip(logicalPath string, file FileStoreContent, destPath string) bool {
+	if file.Checksum == "" {
+		return false
+	}
+	entry, ok := m.get(logicalPath)
+	if !ok || entry.Digest != file.Checksum {
+		return false
+	}
+	info, err := os.Stat(destPath)
+	if err != nil {
+		return false
+	}
+	return info.Size() == entry.Size && info.ModTime().Equal(entry.ModTime)
+}
+
+// digest computes the hex-encoded digest of data using algo, defaulting to SHA-256
+// when algo is empty.
+func digest(algo string, data []byte) (string, error) {
+	switch strings.ToLower(algo) {
+	case "", "sha256":
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:]), nil
+	case "sha1":
+		sum := sha1.Sum(data)
+		return hex.EncodeToString(sum[:]), nil
+	case "md5":
+		sum := md5.Sum(data)
+		return hex.EncodeToString(sum[:]), nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q", algo)
+	}
+}
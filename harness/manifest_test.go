@@ -0,0 +1,92 @@
+package harness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadFileVerifiesChecksum(t *testing.T) {
+	const content = "verified file content"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	file := FileStoreContent{
+		Identifier: "checksum-ok",
+		Name:       "data.yaml",
+		Path:       "/data.yaml",
+		Checksum:   sha256Hex(content),
+	}
+
+	err := file.DownloadFile(api, "test-account", "", "", "/account")
+	require.NoError(t, err)
+	assert.FileExists(t, "./filestore/account/data.yaml")
+}
+
+func TestDownloadFileRejectsChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("corrupted in transit"))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	file := FileStoreContent{
+		Identifier: "checksum-bad",
+		Name:       "data.yaml",
+		Path:       "/data.yaml",
+		Checksum:   sha256Hex("expected content"),
+	}
+
+	err := file.DownloadFile(api, "test-account", "", "", "/account")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+	assert.NoFileExists(t, "./filestore/account/data.yaml")
+
+	_, statErr := os.Stat("./filestore/account")
+	assert.True(t, os.IsNotExist(statErr), "nothing should be written to disk after a checksum mismatch")
+}
+
+func TestDownloadFileSkipsUnchangedOnRerun(t *testing.T) {
+	const content = "idempotent content"
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+	defer os.RemoveAll("./filestore")
+
+	api := &APIRequest{BaseURL: server.URL, Client: resty.New(), APIKey: "test-api-key"}
+	file := FileStoreContent{
+		Identifier: "idempotent",
+		Name:       "data.yaml",
+		Path:       "/data.yaml",
+		Checksum:   sha256Hex(content),
+	}
+
+	require.NoError(t, file.DownloadFile(api, "test-account", "", "", "/account"))
+	require.NoError(t, file.DownloadFile(api, "test-account", "", "", "/account"))
+
+	assert.Equal(t, int32(1), hits, "second run should skip the already up-to-date file")
+}
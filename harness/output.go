@@ -0,0 +1,50 @@
+package harness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Output is the write target DownloadFile/DownloadFiles persist verified bytes to.
+// DiskOutput, the default, lays files out under ./filestore/<folder> as before;
+// ArchiveWriter instead streams them into a single .tar.gz or .zip bundle.
+type Output interface {
+	// Write persists data as the file at folder+path, scoped to account/org/project,
+	// and returns the number of bytes written.
+	Write(account, org, project, folder, path string, data []byte) (int64, error)
+}
+
+// DiskOutput writes files beneath ./filestore/<folder>, the historical on-disk
+// layout, via a temp file that's only renamed into place once fully written.
+type DiskOutput struct{}
+
+// Write implements Output.
+func (DiskOutput) Write(account, org, project, folder, path string, data []byte) (int64, error) {
+	destPath := filepath.Join("filestore", folder, path)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return 0, fmt.Errorf("creating directory for %s: %w", destPath, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".download-*")
+	if err != nil {
+		return 0, fmt.Errorf("creating temp file for %s: %w", destPath, err)
+	}
+	tmpPath := tmp.Name()
+
+	n, err := tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return int64(n), fmt.Errorf("writing file %s: %w", destPath, err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return int64(n), fmt.Errorf("finalizing file %s: %w", destPath, err)
+	}
+
+	return int64(n), nil
+}
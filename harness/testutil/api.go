@@ -0,0 +1,116 @@
+// Package testutil provides recorded-HTTP-interaction helpers for harness tests,
+// wrapping go-vcr's recorder around the resty client an APIRequest carries so tests
+// can replay real Harness API response shapes (rate limit headers, error bodies)
+// without depending on network access or live credentials.
+package testutil
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"gopkg.in/dnaeon/go-vcr.v3/cassette"
+	"gopkg.in/dnaeon/go-vcr.v3/recorder"
+
+	"github.com/frankstutz-at-globality/HarnessRemoteMigrator/harness"
+)
+
+// baseURL is the real Harness API endpoint cassettes are recorded against. Recording
+// and replaying both use it so the recorder's request matcher sees identical URLs.
+const baseURL = "https://app.harness.io"
+
+// NewRecordingAPI returns an APIRequest whose traffic is recorded to
+// testdata/fixtures/<fixture>.yaml. API keys and account/org/project identifiers are
+// scrubbed from each interaction before it's written, so the cassette is safe to
+// commit. Run tests with this helper once against a real sandbox account to record
+// a fixture, then switch the test back to NewReplayingAPI. A fixture that already
+// exists is replayed rather than re-recorded, so delete it first to refresh it.
+func NewRecordingAPI(t *testing.T, fixture string) *harness.APIRequest {
+	t.Helper()
+	return newAPI(t, fixture, recorder.ModeRecordOnce)
+}
+
+// NewReplayingAPI returns an APIRequest that replays the cassette at
+// testdata/fixtures/<fixture>.yaml instead of making real HTTP calls, so tests stay
+// deterministic and offline in CI.
+func NewReplayingAPI(t *testing.T, fixture string) *harness.APIRequest {
+	t.Helper()
+	return newAPI(t, fixture, recorder.ModeReplayOnly)
+}
+
+func newAPI(t *testing.T, fixture string, mode recorder.Mode) *harness.APIRequest {
+	t.Helper()
+
+	rec, err := recorder.NewWithOptions(&recorder.Options{
+		CassetteName: filepath.Join("testdata", "fixtures", fixture),
+		Mode:         mode,
+	})
+	if err != nil {
+		t.Fatalf("opening cassette %s: %v", fixture, err)
+	}
+	rec.AddHook(scrub, recorder.BeforeSaveHook)
+	rec.SetMatcher(matchScrubbed)
+	t.Cleanup(func() {
+		if err := rec.Stop(); err != nil {
+			t.Errorf("saving cassette %s: %v", fixture, err)
+		}
+	})
+
+	client := resty.New()
+	client.GetClient().Transport = rec
+
+	return &harness.APIRequest{
+		BaseURL: baseURL,
+		Client:  client,
+		APIKey:  "test-api-key",
+	}
+}
+
+// scrub strips the API key header from a recorded interaction and replaces its
+// non-empty account/org/project query parameters with placeholders before it's
+// persisted to disk.
+func scrub(i *cassette.Interaction) error {
+	i.Request.Headers.Del("X-Api-Key")
+	i.Request.Headers.Del("Authorization")
+
+	u, err := url.Parse(i.Request.URL)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = scrubQuery(u.Query()).Encode()
+	i.Request.URL = u.String()
+
+	return nil
+}
+
+// scrubQuery replaces the account/org/project identifiers in q with fixed
+// placeholders, matching what scrub bakes into a cassette at record time.
+func scrubQuery(q url.Values) url.Values {
+	for _, key := range []string{"accountIdentifier", "orgIdentifier", "projectIdentifier"} {
+		if q.Get(key) != "" {
+			q.Set(key, "scrubbed-"+key)
+		}
+	}
+	return q
+}
+
+// matchScrubbed matches a live request against a cassette interaction on method,
+// path, and query with the account/org/project identifiers normalized the same way
+// scrub rewrites them before saving. Without this, replay would compare the live
+// request's real identifiers against the scrubbed values baked into the fixture and
+// never find a match.
+func matchScrubbed(r *http.Request, i cassette.Request) bool {
+	if r.Method != i.Method {
+		return false
+	}
+
+	casURL, err := url.Parse(i.URL)
+	if err != nil {
+		return false
+	}
+
+	return r.URL.Path == casURL.Path &&
+		scrubQuery(r.URL.Query()).Encode() == scrubQuery(casURL.Query()).Encode()
+}